@@ -0,0 +1,44 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command shoot-dns-service-nameserver is the binary deployed by the nameserver controller
+// into shoots with --deploy-in-cluster-nameserver set; see pkg/controller/nameserver/server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/nameserver/server"
+)
+
+func main() {
+	opts := server.Options{}
+	flag.StringVar(&opts.Addr, "addr", ":5353", "address the DNS server listens on")
+	flag.StringVar(&opts.HealthAddr, "health-addr", ":8080", "address the health/readiness endpoints are served on")
+	flag.StringVar(&opts.ZoneFile, "zone-file", "", "path of the mounted ConfigMap entry holding the JSON zone")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := server.New(opts).Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "shoot-dns-service-nameserver: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -22,6 +22,10 @@ import (
 	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/config"
 	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/healthcheck"
 	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/lifecycle"
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/nameserver"
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/ownerdns"
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/plan"
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/providers"
 	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/replication"
 	healthcheckconfig "github.com/gardener/gardener/extensions/pkg/apis/config"
 	"github.com/gardener/gardener/extensions/pkg/controller/cmd"
@@ -33,19 +37,27 @@ import (
 
 // DNSServiceOptions holds options related to the dns service.
 type DNSServiceOptions struct {
-	SeedID                    string
-	DNSClass                  string
-	ManageDNSProviders        bool
-	ReplicateDNSProviders     bool
-	OwnerDNSActivation        bool
-	RemoteDefaultDomainSecret string
-	config                    *DNSServiceConfig
+	SeedID                             string
+	DNSClass                           string
+	ManageDNSProviders                 bool
+	ReplicateDNSProviders              bool
+	OwnerDNSActivation                 bool
+	RemoteDefaultDomainSecret          string
+	LifecycleMaxConcurrentReconciles   int
+	ReplicationMaxConcurrentReconciles int
+	DNSProviderFetchParallelism        int
+	DNSProviderFetchTimeout            time.Duration
+	OwnerDNSCleanupMode                string
+	DeployInClusterNameserver          bool
+	DNSChangeMode                      string
+	config                             *DNSServiceConfig
 }
 
 // HealthOptions holds options for health checks.
 type HealthOptions struct {
-	HealthCheckSyncPeriod time.Duration
-	config                *HealthConfig
+	HealthCheckSyncPeriod              time.Duration
+	HealthCheckMaxConcurrentReconciles int
+	config                             *HealthConfig
 }
 
 // AddFlags implements Flagger.AddFlags.
@@ -54,13 +66,22 @@ func (o *DNSServiceOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.DNSClass, "dns-class", "garden", "DNS class used to filter DNS source resources in shoot clusters")
 	fs.BoolVar(&o.ManageDNSProviders, "manage-dns-providers", false, "enables management of DNSProviders in control plane (must only be enable if Gardenlet has disabled it)")
 	fs.BoolVar(&o.ReplicateDNSProviders, "replicate-dns-providers", false, "enables replication of DNSProviders from shoot cluster to seed cluster")
-	fs.BoolVar(&o.OwnerDNSActivation, "enable-owner-dns-activation", false, "enables DNS activation of the shootdns DNSOwner")
+	fs.BoolVar(&o.OwnerDNSActivation, "enable-owner-dns-activation", false, "enables DNS activation of the shootdns DNSOwner (deprecated, use --owner-dns-cleanup instead)")
+	_ = fs.MarkDeprecated("enable-owner-dns-activation", "use --owner-dns-cleanup instead; owner checks are being retired in favor of HA control planes")
 	fs.StringVar(&o.RemoteDefaultDomainSecret, "remote-default-domain-secret", "", "secret name for default 'external' DNSProvider DNS class used to filter DNS source resources in shoot clusters")
+	fs.IntVar(&o.LifecycleMaxConcurrentReconciles, "lifecycle-max-concurrent-reconciles", 1, "number of concurrent reconciles the lifecycle controller may run")
+	fs.IntVar(&o.ReplicationMaxConcurrentReconciles, "replication-max-concurrent-reconciles", 1, "number of concurrent reconciles the replication controller may run")
+	fs.IntVar(&o.DNSProviderFetchParallelism, "dns-provider-fetch-parallelism", 4, "maximum number of DNSProvider/DNSEntry status fetches to run concurrently per reconcile")
+	fs.DurationVar(&o.DNSProviderFetchTimeout, "dns-provider-fetch-timeout", time.Second*30, "timeout for a single DNSProvider/DNSEntry status fetch")
+	fs.StringVar(&o.OwnerDNSCleanupMode, "owner-dns-cleanup", string(ownerdns.CleanupModeOff), "controls the rollout of the DNSOwner cleanup migration: 'off', 'deprecate-warn', or 'migrate-and-remove'")
+	fs.BoolVar(&o.DeployInClusterNameserver, "deploy-in-cluster-nameserver", false, "deploys a small in-cluster nameserver into the shoot that answers for its own managed DNS zones")
+	fs.StringVar(&o.DNSChangeMode, "dns-change-mode", string(plan.ModeApply), "controls how computed DNSEntry changes are rolled out: 'apply', 'plan', or 'require-approval'")
 }
 
 // AddFlags implements Flagger.AddFlags.
 func (o *HealthOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&o.HealthCheckSyncPeriod, "healthcheck-sync-period", time.Second*30, "sync period for the health check controller")
+	fs.IntVar(&o.HealthCheckMaxConcurrentReconciles, "healthcheck-max-concurrent-reconciles", 1, "number of concurrent reconciles the healthcheck controller may run")
 }
 
 // Complete implements Completer.Complete.
@@ -77,20 +98,58 @@ func (o *DNSServiceOptions) Complete() error {
 		}
 	}
 
+	cleanupMode := ownerdns.CleanupMode(o.OwnerDNSCleanupMode)
+	switch cleanupMode {
+	case ownerdns.CleanupModeOff, ownerdns.CleanupModeDeprecateWarn, ownerdns.CleanupModeMigrateAndRemove:
+	default:
+		return fmt.Errorf("invalid value for owner-dns-cleanup: %s (expected one of %q, %q, %q)",
+			o.OwnerDNSCleanupMode, ownerdns.CleanupModeOff, ownerdns.CleanupModeDeprecateWarn, ownerdns.CleanupModeMigrateAndRemove)
+	}
+
+	changeMode := plan.Mode(o.DNSChangeMode)
+	switch changeMode {
+	case plan.ModeApply, plan.ModePlan, plan.ModeRequireApproval:
+	default:
+		return fmt.Errorf("invalid value for dns-change-mode: %s (expected one of %q, %q, %q)",
+			o.DNSChangeMode, plan.ModeApply, plan.ModePlan, plan.ModeRequireApproval)
+	}
+
 	o.config = &DNSServiceConfig{
-		SeedID:                    o.SeedID,
-		DNSClass:                  o.DNSClass,
-		ManageDNSProviders:        o.ManageDNSProviders,
-		ReplicateDNSProviders:     o.ReplicateDNSProviders,
-		OwnerDNSActivation:        o.OwnerDNSActivation,
-		RemoteDefaultDomainSecret: remoteDefaultDomainSecret,
+		SeedID:                             o.SeedID,
+		DNSClass:                           o.DNSClass,
+		ManageDNSProviders:                 o.ManageDNSProviders,
+		ReplicateDNSProviders:              o.ReplicateDNSProviders,
+		OwnerDNSActivation:                 o.OwnerDNSActivation,
+		RemoteDefaultDomainSecret:          remoteDefaultDomainSecret,
+		LifecycleMaxConcurrentReconciles:   o.LifecycleMaxConcurrentReconciles,
+		ReplicationMaxConcurrentReconciles: o.ReplicationMaxConcurrentReconciles,
+		DNSProviderFetchParallelism:        o.DNSProviderFetchParallelism,
+		DNSProviderFetchTimeout:            o.DNSProviderFetchTimeout,
+		OwnerDNSCleanupMode:                o.OwnerDNSCleanupMode,
+		DeployInClusterNameserver:          o.DeployInClusterNameserver,
+		DNSChangeMode:                      o.DNSChangeMode,
 	}
+
+	lifecycle.DefaultAddOptions.Controller.MaxConcurrentReconciles = o.LifecycleMaxConcurrentReconciles
+	replication.DefaultAddOptions.Controller.MaxConcurrentReconciles = o.ReplicationMaxConcurrentReconciles
+	ownerdns.DefaultAddOptions.CleanupMode = cleanupMode
+	nameserver.DefaultAddOptions.Enabled = o.DeployInClusterNameserver
+	lifecycle.DefaultAddOptions.ChangeMode = changeMode
+
+	fetcher := providers.NewFetcher(o.DNSProviderFetchParallelism, o.DNSProviderFetchTimeout)
+	lifecycle.DefaultAddOptions.Fetcher = fetcher
+	replication.DefaultAddOptions.Fetcher = fetcher
 	return nil
 }
 
 // Complete implements Completer.Complete.
 func (o *HealthOptions) Complete() error {
-	o.config = &HealthConfig{HealthCheckSyncPeriod: metav1.Duration{Duration: o.HealthCheckSyncPeriod}}
+	o.config = &HealthConfig{
+		HealthCheckSyncPeriod:              metav1.Duration{Duration: o.HealthCheckSyncPeriod},
+		HealthCheckMaxConcurrentReconciles: o.HealthCheckMaxConcurrentReconciles,
+	}
+
+	healthcheck.DefaultAddOptions.Controller.MaxConcurrentReconciles = o.HealthCheckMaxConcurrentReconciles
 	return nil
 }
 
@@ -106,12 +165,19 @@ func (o *HealthOptions) Completed() *HealthConfig {
 
 // DNSServiceConfig contains configuration information about the dns service.
 type DNSServiceConfig struct {
-	SeedID                    string
-	DNSClass                  string
-	ManageDNSProviders        bool
-	ReplicateDNSProviders     bool
-	OwnerDNSActivation        bool
-	RemoteDefaultDomainSecret *types.NamespacedName
+	SeedID                             string
+	DNSClass                           string
+	ManageDNSProviders                 bool
+	ReplicateDNSProviders              bool
+	OwnerDNSActivation                 bool
+	RemoteDefaultDomainSecret          *types.NamespacedName
+	LifecycleMaxConcurrentReconciles   int
+	ReplicationMaxConcurrentReconciles int
+	DNSProviderFetchParallelism        int
+	DNSProviderFetchTimeout            time.Duration
+	OwnerDNSCleanupMode                string
+	DeployInClusterNameserver          bool
+	DNSChangeMode                      string
 }
 
 // Apply applies the DNSServiceOptions to the passed ControllerOptions instance.
@@ -126,7 +192,8 @@ func (c *DNSServiceConfig) Apply(cfg *config.DNSServiceConfig) {
 
 // HealthConfig contains configuration information about the health check controller.
 type HealthConfig struct {
-	HealthCheckSyncPeriod metav1.Duration
+	HealthCheckSyncPeriod              metav1.Duration
+	HealthCheckMaxConcurrentReconciles int
 }
 
 // ApplyHealthCheckConfig applies the `HealthConfig` to the passed health configurtaion.
@@ -139,6 +206,8 @@ func ControllerSwitches() *cmd.SwitchOptions {
 	return cmd.NewSwitchOptions(
 		cmd.Switch(lifecycle.Name, lifecycle.AddToManager),
 		cmd.Switch(replication.Name, replication.AddToManager),
+		cmd.Switch(ownerdns.Name, ownerdns.AddToManager),
+		cmd.Switch(nameserver.Name, nameserver.AddToManager),
 		cmd.Switch(extensionshealthcheckcontroller.ControllerName, healthcheck.RegisterHealthChecks),
 	)
 }
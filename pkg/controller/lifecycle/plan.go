@@ -0,0 +1,111 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	dnsv1alpha1 "github.com/gardener/external-dns-management/pkg/apis/dns/v1alpha1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controllerutil"
+
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/plan"
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/providers"
+)
+
+// dnsConfig is the shoot-dns-service ProviderConfig gardenlet writes onto the Extension
+// resource: the DNSEntries that the shoot's DNS sources (Ingresses, Services, explicit
+// DNSEntry sources, ...) want to exist, already resolved to concrete targets.
+type dnsConfig struct {
+	Entries []dnsEntrySpec `json:"entries,omitempty"`
+}
+
+type dnsEntrySpec struct {
+	Name    string   `json:"name"`
+	DNSName string   `json:"dnsName"`
+	Targets []string `json:"targets,omitempty"`
+}
+
+// desiredEntries decodes the DNSEntries the shoot's DNS sources want from the Extension's
+// ProviderConfig. A missing ProviderConfig means the shoot currently wants no managed
+// DNSEntries at all, not that the desired state is unknown.
+func desiredEntries(ext *extensionsv1alpha1.Extension) ([]dnsv1alpha1.DNSEntry, error) {
+	if ext.Spec.ProviderConfig == nil {
+		return nil, nil
+	}
+	var cfg dnsConfig
+	if err := json.Unmarshal(ext.Spec.ProviderConfig.Raw, &cfg); err != nil {
+		return nil, fmt.Errorf("decoding dns provider config: %w", err)
+	}
+
+	entries := make([]dnsv1alpha1.DNSEntry, 0, len(cfg.Entries))
+	for _, e := range cfg.Entries {
+		entries = append(entries, dnsv1alpha1.DNSEntry{
+			ObjectMeta: metav1.ObjectMeta{Name: e.Name, Namespace: ext.Namespace},
+			Spec:       dnsv1alpha1.DNSEntrySpec{DNSName: e.DNSName, Targets: e.Targets},
+		})
+	}
+	return entries, nil
+}
+
+// currentEntries converts the DNSEntries gathered by the Fetcher into their typed form so
+// they can be compared against the desired state by plan.Compute.
+func currentEntries(results []providers.Result) ([]dnsv1alpha1.DNSEntry, error) {
+	var entries []dnsv1alpha1.DNSEntry
+	for _, result := range results {
+		for _, raw := range result.Entries {
+			entry := dnsv1alpha1.DNSEntry{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw.Object, &entry); err != nil {
+				return nil, fmt.Errorf("converting dnsentry %s/%s: %w", raw.GetNamespace(), raw.GetName(), err)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// applyPlan pushes the additions, updates and removals of a computed Plan to the DNSEntries
+// in namespace, so external-dns-management picks them up and reconciles them against the
+// actual DNS provider.
+func applyPlan(ctx context.Context, c client.Client, namespace string, p *plan.Plan) error {
+	for _, zone := range p.Zones {
+		for _, change := range append(append([]plan.Change{}, zone.Additions...), zone.Updates...) {
+			name := strings.TrimPrefix(change.Name, namespace+"/")
+			entry := &dnsv1alpha1.DNSEntry{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+			if _, err := controllerutil.CreateOrUpdate(ctx, c, entry, func() error {
+				entry.Spec.DNSName = change.DNSName
+				entry.Spec.Targets = change.Targets
+				return nil
+			}); err != nil {
+				return fmt.Errorf("applying dnsentry %s: %w", change.Name, err)
+			}
+		}
+		for _, change := range zone.Removals {
+			name := strings.TrimPrefix(change.Name, namespace+"/")
+			entry := &dnsv1alpha1.DNSEntry{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+			if err := c.Delete(ctx, entry); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("removing dnsentry %s: %w", change.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/plan"
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/providers"
+)
+
+// Reconciler reconciles the lifecycle of a shoot's managed DNS records: it gathers the
+// current DNSProvider/DNSEntry status for the shoot's control-plane namespace through the
+// bounded Fetcher so a single slow provider cannot stall reconciliation of other shoots.
+type Reconciler struct {
+	client     client.Client
+	recorder   record.EventRecorder
+	fetcher    *providers.Fetcher
+	changeMode plan.Mode
+}
+
+// Reconcile fetches the current DNSProvider/DNSEntry status for the Extension's
+// control-plane namespace and surfaces any per-namespace fetch failure so the request is
+// retried instead of silently reconciling against a stale or incomplete picture.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ext := &extensionsv1alpha1.Extension{}
+	if err := r.client.Get(ctx, req.NamespacedName, ext); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if ext.Spec.Type != Type {
+		return reconcile.Result{}, nil
+	}
+
+	results, err := r.fetcher.FetchAll(ctx, r.client, []string{req.Namespace})
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("fetching dns provider status for %s: %w", req.Namespace, err)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			r.recorder.Eventf(ext, "Warning", "DNSStatusFetchFailed", "%v", result.Err)
+			return reconcile.Result{}, result.Err
+		}
+	}
+
+	current, err := currentEntries(results)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("decoding current dns entries for %s: %w", req.Namespace, err)
+	}
+	desired, err := desiredEntries(ext)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("decoding desired dns entries for %s: %w", req.Namespace, err)
+	}
+
+	p, err := plan.Compute(desired, current)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("computing dns change plan for %s: %w", req.Namespace, err)
+	}
+
+	switch r.changeMode {
+	case plan.ModePlan:
+		if err := plan.Record(ctx, r.client, r.recorder, ext, p); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	case plan.ModeRequireApproval:
+		if err := plan.Record(ctx, r.client, r.recorder, ext, p); err != nil {
+			return reconcile.Result{}, err
+		}
+		if !p.IsApproved(ext.Annotations) {
+			r.recorder.Eventf(ext, "Normal", "DNSChangeAwaitingApproval",
+				"dns change plan %s awaits approval via the %s annotation", p.Hash, plan.ApprovedPlanHashAnnotation)
+			return reconcile.Result{}, nil
+		}
+	}
+
+	if err := applyPlan(ctx, r.client, req.Namespace, p); err != nil {
+		return reconcile.Result{}, fmt.Errorf("applying dns change plan for %s: %w", req.Namespace, err)
+	}
+	return reconcile.Result{}, nil
+}
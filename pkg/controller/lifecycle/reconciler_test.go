@@ -0,0 +1,262 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	dnsv1alpha1 "github.com/gardener/external-dns-management/pkg/apis/dns/v1alpha1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/plan"
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/providers"
+)
+
+// fakeLifecycleClient backs a single Extension plus a DNSEntry store. It overrides only the
+// methods Reconcile's path actually exercises (Get/List/Patch/Status/Create/Update/Delete);
+// everything else is left to the nil embedded client.Client.
+type fakeLifecycleClient struct {
+	client.Client
+	ext     *extensionsv1alpha1.Extension
+	current []dnsv1alpha1.DNSEntry
+	entries map[string]*dnsv1alpha1.DNSEntry
+
+	patched       bool
+	statusPatched bool
+}
+
+func newFakeLifecycleClient(ext *extensionsv1alpha1.Extension, current []dnsv1alpha1.DNSEntry) *fakeLifecycleClient {
+	return &fakeLifecycleClient{ext: ext, current: current, entries: map[string]*dnsv1alpha1.DNSEntry{}}
+}
+
+func (c *fakeLifecycleClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	switch o := obj.(type) {
+	case *extensionsv1alpha1.Extension:
+		*o = *c.ext.DeepCopy()
+		return nil
+	case *dnsv1alpha1.DNSEntry:
+		existing, ok := c.entries[key.String()]
+		if !ok {
+			return apierrors.NewNotFound(schema.GroupResource{Group: "dns.gardener.cloud", Resource: "dnsentries"}, key.Name)
+		}
+		*o = *existing.DeepCopy()
+		return nil
+	default:
+		return fmt.Errorf("fakeLifecycleClient.Get: unexpected object type %T", obj)
+	}
+}
+
+func (c *fakeLifecycleClient) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	ul, ok := list.(*unstructured.UnstructuredList)
+	if !ok {
+		return fmt.Errorf("fakeLifecycleClient.List: unexpected list type %T", list)
+	}
+	if ul.GetKind() != "DNSEntryList" {
+		return nil
+	}
+	items := make([]unstructured.Unstructured, 0, len(c.current))
+	for _, e := range c.current {
+		raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&e)
+		if err != nil {
+			return err
+		}
+		items = append(items, unstructured.Unstructured{Object: raw})
+	}
+	ul.Items = items
+	return nil
+}
+
+func (c *fakeLifecycleClient) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	ext, ok := obj.(*extensionsv1alpha1.Extension)
+	if !ok {
+		return fmt.Errorf("fakeLifecycleClient.Patch: unexpected object type %T", obj)
+	}
+	c.patched = true
+	c.ext = ext.DeepCopy()
+	return nil
+}
+
+func (c *fakeLifecycleClient) Status() client.SubResourceWriter {
+	return &fakeLifecycleStatusWriter{client: c}
+}
+
+type fakeLifecycleStatusWriter struct {
+	client.SubResourceWriter
+	client *fakeLifecycleClient
+}
+
+func (w *fakeLifecycleStatusWriter) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	ext, ok := obj.(*extensionsv1alpha1.Extension)
+	if !ok {
+		return fmt.Errorf("fakeLifecycleStatusWriter.Patch: unexpected object type %T", obj)
+	}
+	w.client.statusPatched = true
+	w.client.ext = ext.DeepCopy()
+	return nil
+}
+
+func (c *fakeLifecycleClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	entry, ok := obj.(*dnsv1alpha1.DNSEntry)
+	if !ok {
+		return fmt.Errorf("fakeLifecycleClient.Create: unexpected object type %T", obj)
+	}
+	c.entries[types.NamespacedName{Namespace: entry.Namespace, Name: entry.Name}.String()] = entry.DeepCopy()
+	return nil
+}
+
+func (c *fakeLifecycleClient) Update(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	return c.Create(ctx, obj)
+}
+
+func (c *fakeLifecycleClient) Delete(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	entry, ok := obj.(*dnsv1alpha1.DNSEntry)
+	if !ok {
+		return fmt.Errorf("fakeLifecycleClient.Delete: unexpected object type %T", obj)
+	}
+	key := types.NamespacedName{Namespace: entry.Namespace, Name: entry.Name}.String()
+	if _, ok := c.entries[key]; !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Group: "dns.gardener.cloud", Resource: "dnsentries"}, entry.Name)
+	}
+	delete(c.entries, key)
+	return nil
+}
+
+func newTestExtension(annotations map[string]string) *extensionsv1alpha1.Extension {
+	return &extensionsv1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{Name: "shoot-dns-service", Namespace: "shoot--foo--bar", Annotations: annotations},
+		Spec: extensionsv1alpha1.ExtensionSpec{
+			DefaultSpec: extensionsv1alpha1.DefaultSpec{
+				Type: Type,
+				ProviderConfig: &runtime.RawExtension{
+					Raw: []byte(`{"entries":[{"name":"a","dnsName":"a.example.com","targets":["1.2.3.4"]}]}`),
+				},
+			},
+		},
+	}
+}
+
+func testReconcileRequest() reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "shoot--foo--bar", Name: "shoot-dns-service"}}
+}
+
+func TestReconcileApplyModeAppliesWithoutRecording(t *testing.T) {
+	c := newFakeLifecycleClient(newTestExtension(nil), nil)
+	r := &Reconciler{
+		client:     c,
+		recorder:   record.NewFakeRecorder(10),
+		fetcher:    providers.NewFetcher(1, time.Second),
+		changeMode: plan.ModeApply,
+	}
+
+	if _, err := r.Reconcile(context.Background(), testReconcileRequest()); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	if c.patched || c.statusPatched {
+		t.Fatal("expected apply mode to never record the plan on the extension")
+	}
+	entry, ok := c.entries["shoot--foo--bar/a"]
+	if !ok {
+		t.Fatal("expected apply mode to create the missing dnsentry")
+	}
+	if entry.Spec.DNSName != "a.example.com" {
+		t.Fatalf("expected dnsentry a to target a.example.com, got %s", entry.Spec.DNSName)
+	}
+}
+
+func TestReconcilePlanModeRecordsWithoutApplying(t *testing.T) {
+	c := newFakeLifecycleClient(newTestExtension(nil), nil)
+	r := &Reconciler{
+		client:     c,
+		recorder:   record.NewFakeRecorder(10),
+		fetcher:    providers.NewFetcher(1, time.Second),
+		changeMode: plan.ModePlan,
+	}
+
+	if _, err := r.Reconcile(context.Background(), testReconcileRequest()); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	if !c.patched || !c.statusPatched {
+		t.Fatal("expected plan mode to record the computed plan on the extension")
+	}
+	if len(c.entries) != 0 {
+		t.Fatal("expected plan mode to never apply the computed changes")
+	}
+}
+
+func TestReconcileRequireApprovalRecordsAndWithholdsUntilApproved(t *testing.T) {
+	ext := newTestExtension(nil)
+	c := newFakeLifecycleClient(ext, nil)
+	r := &Reconciler{
+		client:     c,
+		recorder:   record.NewFakeRecorder(10),
+		fetcher:    providers.NewFetcher(1, time.Second),
+		changeMode: plan.ModeRequireApproval,
+	}
+
+	if _, err := r.Reconcile(context.Background(), testReconcileRequest()); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	if !c.patched || !c.statusPatched {
+		t.Fatal("expected require-approval mode to record the computed plan on the extension")
+	}
+	if len(c.entries) != 0 {
+		t.Fatal("expected an unapproved plan to never be applied")
+	}
+}
+
+func TestReconcileRequireApprovalAppliesOnceApproved(t *testing.T) {
+	ext := newTestExtension(nil)
+
+	desired, err := desiredEntries(ext)
+	if err != nil {
+		t.Fatalf("desiredEntries: %v", err)
+	}
+	p, err := plan.Compute(desired, nil)
+	if err != nil {
+		t.Fatalf("plan.Compute: %v", err)
+	}
+	ext.Annotations = map[string]string{plan.ApprovedPlanHashAnnotation: p.Hash}
+
+	c := newFakeLifecycleClient(ext, nil)
+	r := &Reconciler{
+		client:     c,
+		recorder:   record.NewFakeRecorder(10),
+		fetcher:    providers.NewFetcher(1, time.Second),
+		changeMode: plan.ModeRequireApproval,
+	}
+
+	if _, err := r.Reconcile(context.Background(), testReconcileRequest()); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	if _, ok := c.entries["shoot--foo--bar/a"]; !ok {
+		t.Fatal("expected an approved plan to be applied")
+	}
+}
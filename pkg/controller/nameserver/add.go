@@ -0,0 +1,68 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nameserver optionally deploys a small in-cluster nameserver into the shoot,
+// answering A/AAAA/CNAME queries for the shoot's own managed DNS zones directly from the
+// records already tracked as DNSEntries, so that in-cluster workloads do not have to wait
+// for the records to propagate through the external provider.
+package nameserver
+
+import (
+	"fmt"
+
+	dnsv1alpha1 "github.com/gardener/external-dns-management/pkg/apis/dns/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/replication"
+)
+
+// Name is the name of the in-cluster nameserver controller.
+const Name = "shoot_dns_service_nameserver"
+
+// AddOptions are options to apply when adding the nameserver controller to the manager.
+type AddOptions struct {
+	// Controller contains options for the controller.
+	Controller controller.Options
+	// Enabled controls whether the in-cluster nameserver is deployed for managed shoots.
+	Enabled bool
+}
+
+// DefaultAddOptions are the default AddOptions for AddToManager.
+var DefaultAddOptions = AddOptions{}
+
+// AddToManager adds the nameserver controller to the given manager. It is a no-op unless
+// --deploy-in-cluster-nameserver has been set.
+func AddToManager(mgr manager.Manager) error {
+	if !DefaultAddOptions.Enabled {
+		return nil
+	}
+
+	r := &Reconciler{
+		client:          mgr.GetClient(),
+		shootClientFunc: replication.NewShootClient,
+	}
+
+	c, err := controller.New(Name, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: DefaultAddOptions.Controller.MaxConcurrentReconciles,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create %s controller: %w", Name, err)
+	}
+
+	return c.Watch(source.Kind(mgr.GetCache(), &dnsv1alpha1.DNSEntry{}), &handler.EnqueueRequestForObject{})
+}
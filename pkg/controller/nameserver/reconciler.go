@@ -0,0 +1,91 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nameserver
+
+import (
+	"context"
+	"fmt"
+
+	dnsv1alpha1 "github.com/gardener/external-dns-management/pkg/apis/dns/v1alpha1"
+	"github.com/miekg/dns"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// shootClientFunc builds a client for the shoot cluster whose control plane lives in the
+// given seed namespace. It is a field on Reconciler so tests can substitute a fake client.
+type shootClientFunc func(ctx context.Context, seedClient client.Client, namespace string) (client.Client, error)
+
+// Reconciler projects the DNSEntries owned by a shoot into the ConfigMap backing its
+// in-cluster nameserver Deployment, creating the Deployment/Service/ServiceAccount on first sight of
+// a managed entry in a control-plane namespace.
+type Reconciler struct {
+	client          client.Client
+	shootClientFunc shootClientFunc
+}
+
+// Reconcile re-projects the zone ConfigMap for the shoot owning the reconciled DNSEntry and
+// makes sure the nameserver Deployment, Service and ServiceAccount exist in the shoot. A deleted entry
+// still needs to re-project the zone from its remaining siblings, so a NotFound on the Get
+// falls through to the list/project steps below rather than returning early.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	entry := &dnsv1alpha1.DNSEntry{}
+	if err := r.client.Get(ctx, req.NamespacedName, entry); err != nil && !apierrors.IsNotFound(err) {
+		return reconcile.Result{}, fmt.Errorf("getting dnsentry %s: %w", req.NamespacedName, err)
+	}
+
+	entryList := &dnsv1alpha1.DNSEntryList{}
+	if err := r.client.List(ctx, entryList, client.InNamespace(req.Namespace)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("listing dnsentries in %s: %w", req.Namespace, err)
+	}
+
+	shootClient, err := r.shootClientFunc(ctx, r.client, req.Namespace)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("obtaining shoot client for %s: %w", req.Namespace, err)
+	}
+
+	zone := buildZone(entryList.Items)
+
+	if err := reconcileConfigMap(ctx, shootClient, zone); err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconciling nameserver configmap: %w", err)
+	}
+	if err := reconcileDeployment(ctx, shootClient); err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconciling nameserver deployment: %w", err)
+	}
+	if err := reconcileService(ctx, shootClient); err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconciling nameserver service: %w", err)
+	}
+	if err := reconcileServiceAccount(ctx, shootClient); err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconciling nameserver service account: %w", err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// buildZone projects the resolved targets of the given DNSEntries into the record set
+// served by the in-cluster nameserver's ConfigMap, keyed by fully-qualified DNS name since
+// that is how queries arrive in handleQuery's question.Name.
+func buildZone(entries []dnsv1alpha1.DNSEntry) map[string][]string {
+	zone := map[string][]string{}
+	for _, entry := range entries {
+		if entry.Status.State != "Ready" {
+			continue
+		}
+		name := dns.Fqdn(entry.Spec.DNSName)
+		zone[name] = append(zone[name], entry.Spec.Targets...)
+	}
+	return zone
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nameserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controllerutil"
+)
+
+const (
+	resourceNamespace = "kube-system"
+	resourceName      = "shoot-dns-service-nameserver"
+	zoneConfigMapKey  = "zone.json"
+	dnsPort           = 5353
+	healthPort        = 8080
+	// image is pinned to a released tag, built from cmd/shoot-dns-service-nameserver, and
+	// must be bumped alongside it.
+	image = "europe-docker.pkg.dev/gardener-project/releases/shoot-dns-service/nameserver:v0.1.0"
+)
+
+func configMapFor(zone map[string][]string) (*corev1.ConfigMap, error) {
+	data, err := json.Marshal(zone)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling zone: %w", err)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: resourceNamespace},
+		Data:       map[string]string{zoneConfigMapKey: string(data)},
+	}, nil
+}
+
+func reconcileConfigMap(ctx context.Context, c client.Client, zone map[string][]string) error {
+	desired, err := configMapFor(zone)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: resourceNamespace}}
+	_, err = controllerutil.CreateOrUpdate(ctx, c, cm, func() error {
+		cm.Data = desired.Data
+		return nil
+	})
+	return err
+}
+
+func reconcileDeployment(ctx context.Context, c client.Client) error {
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: resourceNamespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, deployment, func() error {
+		labels := map[string]string{"app": resourceName}
+		deployment.Spec = appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: resourceName,
+					Containers: []corev1.Container{{
+						Name:  "nameserver",
+						Image: image,
+						Args: []string{
+							"--zone-file=/config/" + zoneConfigMapKey,
+							fmt.Sprintf("--health-addr=:%d", healthPort),
+						},
+						Ports: []corev1.ContainerPort{
+							{Name: "dns-udp", ContainerPort: dnsPort, Protocol: corev1.ProtocolUDP},
+							{Name: "dns-tcp", ContainerPort: dnsPort, Protocol: corev1.ProtocolTCP},
+							{Name: "health", ContainerPort: healthPort, Protocol: corev1.ProtocolTCP},
+						},
+						VolumeMounts: []corev1.VolumeMount{{Name: "zone", MountPath: "/config", ReadOnly: true}},
+						LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+							Path: "/healthz", Port: intstr.FromInt(healthPort),
+						}}},
+						ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+							Path: "/readyz", Port: intstr.FromInt(healthPort),
+						}}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "zone",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: resourceName}},
+						},
+					}},
+				},
+			},
+		}
+		return nil
+	})
+	return err
+}
+
+func reconcileService(ctx context.Context, c client.Client) error {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: resourceNamespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, svc, func() error {
+		svc.Spec.Selector = map[string]string{"app": resourceName}
+		svc.Spec.Ports = []corev1.ServicePort{
+			{Name: "dns-udp", Port: dnsPort, Protocol: corev1.ProtocolUDP, TargetPort: intstr.FromInt(dnsPort)},
+			{Name: "dns-tcp", Port: dnsPort, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(dnsPort)},
+		}
+		return nil
+	})
+	return err
+}
+
+// reconcileServiceAccount ensures the nameserver's ServiceAccount exists. The nameserver
+// reads its zone purely from the mounted ConfigMap file via fsnotify and never calls the
+// Kubernetes API, so no Role/RoleBinding is granted.
+func reconcileServiceAccount(ctx context.Context, c client.Client) error {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: resourceNamespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, sa, func() error { return nil })
+	return err
+}
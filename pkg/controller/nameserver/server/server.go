@@ -0,0 +1,166 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/miekg/dns"
+)
+
+// Options configures the in-cluster nameserver.
+type Options struct {
+	// Addr is the UDP/TCP address the server listens on for DNS queries, e.g. ":5353".
+	Addr string
+	// HealthAddr is the address the health/readiness endpoints are served on, e.g. ":8080".
+	HealthAddr string
+	// ZoneFile is the path of the mounted ConfigMap entry holding the JSON zone.
+	ZoneFile string
+}
+
+// Server is the in-cluster nameserver. It answers A/AAAA/CNAME for its zone and NXDOMAIN
+// for everything else, and hot-reloads the zone when ZoneFile changes on disk.
+type Server struct {
+	opts Options
+	zone *Zone
+}
+
+// New creates a Server that serves the given options.
+func New(opts Options) *Server {
+	return &Server{opts: opts, zone: NewZone()}
+}
+
+// Run loads the zone, starts the UDP and TCP DNS listeners plus the health/readiness
+// endpoint, watches the zone file for changes, and blocks until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.zone.LoadFile(s.opts.ZoneFile); err != nil {
+		return fmt.Errorf("loading initial zone: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating zone file watcher: %w", err)
+	}
+	defer watcher.Close()
+	// Watch the mounted ConfigMap's directory rather than the zone file itself: kubelet
+	// updates a projected ConfigMap by atomically swapping the directory's ..data symlink
+	// to a new target, so the leaf file's inode never receives a Write/Create event.
+	zoneDir := filepath.Dir(s.opts.ZoneFile)
+	if err := watcher.Add(zoneDir); err != nil {
+		return fmt.Errorf("watching zone directory %s: %w", zoneDir, err)
+	}
+
+	mux := dns.NewServeMux()
+	mux.HandleFunc(".", s.handleQuery)
+
+	udp := &dns.Server{Addr: s.opts.Addr, Net: "udp", Handler: mux}
+	tcp := &dns.Server{Addr: s.opts.Addr, Net: "tcp", Handler: mux}
+
+	errCh := make(chan error, 3)
+	go func() { errCh <- udp.ListenAndServe() }()
+	go func() { errCh <- tcp.ListenAndServe() }()
+	go func() { errCh <- s.serveHealth(ctx) }()
+	go s.watchZoneFile(ctx, watcher)
+
+	select {
+	case <-ctx.Done():
+		_ = udp.ShutdownContext(ctx)
+		_ = tcp.ShutdownContext(ctx)
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
+
+// watchZoneFile reloads the zone from s.opts.ZoneFile whenever watcher reports an event in
+// the ConfigMap's mount directory, until ctx is cancelled or watcher is closed. Any single
+// event may be unrelated to the zone file (e.g. the ..data_tmp symlink kubelet creates
+// mid-swap), so it just reloads unconditionally; LoadFile is cheap and idempotent.
+func (s *Server) watchZoneFile(ctx context.Context, watcher *fsnotify.Watcher) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if err := s.zone.LoadFile(s.opts.ZoneFile); err != nil {
+				// Keep serving the last good zone; the next successful write wins.
+				continue
+			}
+		}
+	}
+}
+
+func (s *Server) handleQuery(w dns.ResponseWriter, req *dns.Msg) {
+	msg := new(dns.Msg)
+	msg.SetReply(req)
+	msg.Authoritative = true
+
+	if len(req.Question) != 1 {
+		msg.Rcode = dns.RcodeFormatError
+		_ = w.WriteMsg(msg)
+		return
+	}
+
+	question := req.Question[0]
+	targets, known := s.zone.Lookup(question.Name)
+	if !known {
+		msg.Rcode = dns.RcodeNameError
+		_ = w.WriteMsg(msg)
+		return
+	}
+
+	if targetsAreIPs(targets) {
+		for _, target := range targets {
+			ip := net.ParseIP(target)
+			if ip.To4() != nil && question.Qtype == dns.TypeA {
+				msg.Answer = append(msg.Answer, &dns.A{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 30}, A: ip})
+			}
+			if ip.To4() == nil && question.Qtype == dns.TypeAAAA {
+				msg.Answer = append(msg.Answer, &dns.AAAA{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: 30}, AAAA: ip})
+			}
+		}
+	} else if question.Qtype == dns.TypeCNAME && len(targets) > 0 {
+		msg.Answer = append(msg.Answer, &dns.CNAME{Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: 30}, Target: dns.Fqdn(targets[0])})
+	}
+
+	_ = w.WriteMsg(msg)
+}
+
+func (s *Server) serveHealth(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	srv := &http.Server{Addr: s.opts.HealthAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// writeZone marshals records into dir/..dataN/zone.json and returns that file's path.
+func writeZone(t *testing.T, dir, dataDirName string, records map[string][]string) string {
+	t.Helper()
+	dataDir := filepath.Join(dir, dataDirName)
+	if err := os.Mkdir(dataDir, 0o700); err != nil {
+		t.Fatalf("mkdir %s: %v", dataDir, err)
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	path := filepath.Join(dataDir, "zone.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write zone file: %v", err)
+	}
+	return path
+}
+
+// TestWatchZoneFileSurvivesConfigMapSymlinkSwap reproduces how kubelet updates a projected
+// ConfigMap mount: it writes the new data to a fresh data directory, points a "..data_tmp"
+// symlink at it, then atomically renames that symlink over the "..data" symlink that
+// zone.json points through. A watch on
+// zone.json's own inode would never see that, so watchZoneFile must watch the directory.
+func TestWatchZoneFileSurvivesConfigMapSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+
+	realDataDir := writeZone(t, dir, "..data_1", map[string][]string{"a.shoot.example.com.": {"1.1.1.1"}})
+	dataLink := filepath.Join(dir, "..data")
+	if err := os.Symlink(filepath.Base(filepath.Dir(realDataDir)), dataLink); err != nil {
+		t.Fatalf("symlink ..data: %v", err)
+	}
+	zoneFile := filepath.Join(dir, "zone.json")
+	if err := os.Symlink(filepath.Join("..data", "zone.json"), zoneFile); err != nil {
+		t.Fatalf("symlink zone.json: %v", err)
+	}
+
+	s := New(Options{ZoneFile: zoneFile})
+	if err := s.zone.LoadFile(s.opts.ZoneFile); err != nil {
+		t.Fatalf("loading initial zone: %v", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("creating watcher: %v", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(dir); err != nil {
+		t.Fatalf("watching %s: %v", dir, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.watchZoneFile(ctx, watcher)
+
+	if _, ok := s.zone.Lookup("b.shoot.example.com."); ok {
+		t.Fatal("precondition: b.shoot.example.com. should not resolve yet")
+	}
+
+	// Simulate kubelet's update: write the new zone to a new data directory, then swap the
+	// ..data symlink to point at it in one atomic rename.
+	_ = writeZone(t, dir, "..data_2", map[string][]string{"b.shoot.example.com.": {"2.2.2.2"}})
+	tmpLink := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink("..data_2", tmpLink); err != nil {
+		t.Fatalf("symlink ..data_tmp: %v", err)
+	}
+	if err := os.Rename(tmpLink, dataLink); err != nil {
+		t.Fatalf("swap ..data symlink: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if targets, ok := s.zone.Lookup("b.shoot.example.com."); ok {
+			if len(targets) != 1 || targets[0] != "2.2.2.2" {
+				t.Fatalf("expected b.shoot.example.com. -> [2.2.2.2], got %v", targets)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for watchZoneFile to pick up the ..data symlink swap")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
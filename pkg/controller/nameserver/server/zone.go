@@ -0,0 +1,77 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server runs the in-cluster nameserver that is deployed into a shoot when
+// --deploy-in-cluster-nameserver is enabled. It answers A/AAAA/CNAME queries for the
+// shoot's own managed zones from an in-memory zone that is hot-reloaded from a mounted
+// ConfigMap, and responds NXDOMAIN for everything else.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+)
+
+// Zone holds the record sets served by the nameserver, keyed by fully-qualified DNS name.
+type Zone struct {
+	mu      sync.RWMutex
+	records map[string][]string
+}
+
+// NewZone returns an empty Zone.
+func NewZone() *Zone {
+	return &Zone{records: map[string][]string{}}
+}
+
+// Lookup returns the targets for name, and whether the name is known to the zone at all
+// (so the caller can distinguish an empty record set from NXDOMAIN).
+func (z *Zone) Lookup(name string) ([]string, bool) {
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+	targets, ok := z.records[name]
+	return targets, ok
+}
+
+// LoadFile reads the JSON-encoded record set written to path by the reconciler's ConfigMap
+// projection and atomically swaps it in.
+func (z *Zone) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading zone file %s: %w", path, err)
+	}
+
+	var records map[string][]string
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("parsing zone file %s: %w", path, err)
+	}
+
+	z.mu.Lock()
+	z.records = records
+	z.mu.Unlock()
+	return nil
+}
+
+// targetsAreIPs reports whether every target in targets parses as an IP address, which
+// distinguishes an A/AAAA record set from a CNAME target.
+func targetsAreIPs(targets []string) bool {
+	for _, t := range targets {
+		if net.ParseIP(t) == nil {
+			return false
+		}
+	}
+	return len(targets) > 0
+}
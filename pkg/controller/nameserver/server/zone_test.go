@@ -0,0 +1,79 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestZoneLookup(t *testing.T) {
+	z := NewZone()
+	z.records = map[string][]string{"a.shoot.example.com.": {"1.1.1.1"}}
+
+	targets, ok := z.Lookup("a.shoot.example.com.")
+	if !ok || len(targets) != 1 || targets[0] != "1.1.1.1" {
+		t.Fatalf("expected known name to resolve to [1.1.1.1], got %v, ok=%v", targets, ok)
+	}
+
+	if _, ok := z.Lookup("unknown.shoot.example.com."); ok {
+		t.Fatal("expected an unknown name to report ok=false so the caller can return NXDOMAIN")
+	}
+}
+
+func TestZoneLoadFile(t *testing.T) {
+	records := map[string][]string{"a.shoot.example.com.": {"1.1.1.1"}}
+	data, err := json.Marshal(records)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "zone.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write zone file: %v", err)
+	}
+
+	z := NewZone()
+	if err := z.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+
+	targets, ok := z.Lookup("a.shoot.example.com.")
+	if !ok || len(targets) != 1 || targets[0] != "1.1.1.1" {
+		t.Fatalf("expected loaded zone to resolve a.shoot.example.com., got %v, ok=%v", targets, ok)
+	}
+}
+
+func TestTargetsAreIPs(t *testing.T) {
+	cases := []struct {
+		name    string
+		targets []string
+		want    bool
+	}{
+		{"empty", nil, false},
+		{"all ips", []string{"1.1.1.1", "2001:db8::1"}, true},
+		{"mixed", []string{"1.1.1.1", "some.host.example.com"}, false},
+		{"cname target", []string{"some.host.example.com"}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := targetsAreIPs(c.targets); got != c.want {
+				t.Fatalf("targetsAreIPs(%v) = %v, want %v", c.targets, got, c.want)
+			}
+		})
+	}
+}
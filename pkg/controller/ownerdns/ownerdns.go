@@ -0,0 +1,154 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ownerdns migrates away from the "owner checks" DNSOwner activation setting that
+// is being retired together with the seed bad-case control-plane migration, now that
+// control planes are highly available. It activates any managed DNSOwner regardless of
+// the (deprecated) `--enable-owner-dns-activation` setting and, once the rollout has
+// progressed, removes the DNSOwner entirely.
+package ownerdns
+
+import (
+	"context"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Name is the name of the owner DNS cleanup migration controller.
+const Name = "shoot_dns_service_owner_dns_cleanup"
+
+// CleanupMode controls the rollout of the DNSOwner cleanup migration.
+type CleanupMode string
+
+const (
+	// CleanupModeOff leaves existing DNSOwner activation behavior untouched.
+	CleanupModeOff CleanupMode = "off"
+	// CleanupModeDeprecateWarn activates every managed DNSOwner and logs a deprecation
+	// warning, but does not remove the resource yet.
+	CleanupModeDeprecateWarn CleanupMode = "deprecate-warn"
+	// CleanupModeMigrateAndRemove activates every managed DNSOwner and removes it once the
+	// owning shoot is no longer in a migration state.
+	CleanupModeMigrateAndRemove CleanupMode = "migrate-and-remove"
+)
+
+var dnsOwnerGVK = schema.GroupVersionKind{Group: "dns.gardener.cloud", Version: "v1alpha1", Kind: "DNSOwner"}
+
+// AddOptions are options to apply when adding the owner DNS cleanup controller to the manager.
+type AddOptions struct {
+	// Controller contains options for the controller.
+	Controller controller.Options
+	// CleanupMode controls whether and how the migration is rolled out.
+	CleanupMode CleanupMode
+}
+
+// DefaultAddOptions are the default AddOptions for AddToManager.
+var DefaultAddOptions = AddOptions{CleanupMode: CleanupModeOff}
+
+// AddToManager adds the owner DNS cleanup controller to the given manager.
+func AddToManager(mgr manager.Manager) error {
+	if DefaultAddOptions.CleanupMode == CleanupModeOff {
+		return nil
+	}
+
+	r := &reconciler{client: mgr.GetClient(), mode: DefaultAddOptions.CleanupMode, log: mgr.GetLogger().WithName(Name)}
+
+	c, err := controller.New(Name, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: DefaultAddOptions.Controller.MaxConcurrentReconciles,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create %s controller: %w", Name, err)
+	}
+
+	owner := &unstructured.Unstructured{}
+	owner.SetGroupVersionKind(dnsOwnerGVK)
+	return c.Watch(source.Kind(mgr.GetCache(), owner), &handler.EnqueueRequestForObject{})
+}
+
+type reconciler struct {
+	client client.Client
+	mode   CleanupMode
+	log    logr.Logger
+}
+
+// Reconcile activates the DNSOwner unconditionally and, in migrate-and-remove mode, removes
+// it once the owning shoot's control-plane namespace is no longer undergoing a migration.
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	owner := &unstructured.Unstructured{}
+	owner.SetGroupVersionKind(dnsOwnerGVK)
+	if err := r.client.Get(ctx, req.NamespacedName, owner); err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("getting dnsowner %s: %w", req.NamespacedName, err)
+	}
+
+	active, _, _ := unstructured.NestedBool(owner.Object, "spec", "active")
+	if !active {
+		if err := unstructured.SetNestedField(owner.Object, true, "spec", "active"); err != nil {
+			return reconcile.Result{}, err
+		}
+		r.log.Info("activating DNSOwner as part of the owner-dns-cleanup migration", "dnsowner", req.NamespacedName)
+		if err := r.client.Update(ctx, owner); err != nil {
+			return reconcile.Result{}, fmt.Errorf("activating dnsowner %s: %w", req.NamespacedName, err)
+		}
+	}
+
+	if r.mode != CleanupModeMigrateAndRemove {
+		if r.mode == CleanupModeDeprecateWarn {
+			r.log.Info("DNSOwner activation is deprecated; this DNSOwner is removed once owner-dns-cleanup is set to migrate-and-remove", "dnsowner", req.NamespacedName)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	migrating, err := r.inMigration(ctx, req.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if migrating {
+		return reconcile.Result{}, nil
+	}
+
+	r.log.Info("removing DNSOwner, owner checks are no longer required", "dnsowner", req.NamespacedName)
+	if err := r.client.Delete(ctx, owner); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, fmt.Errorf("removing dnsowner %s: %w", req.NamespacedName, err)
+	}
+	return reconcile.Result{}, nil
+}
+
+// inMigration reports whether the Extension resource for the shoot control-plane namespace
+// is currently in a migration state.
+func (r *reconciler) inMigration(ctx context.Context, namespace string) (bool, error) {
+	extensionList := &extensionsv1alpha1.ExtensionList{}
+	if err := r.client.List(ctx, extensionList, client.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("listing extensions in %s: %w", namespace, err)
+	}
+	for _, ext := range extensionList.Items {
+		if ext.Status.LastOperation != nil && ext.Status.LastOperation.Type == "Migrate" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ownerdns
+
+import (
+	"context"
+	"testing"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fakeOwnerClient records the Update/Delete calls the reconciler makes against a single
+// DNSOwner and returns a fixed list of Extensions for the migration check.
+type fakeOwnerClient struct {
+	client.Client
+	owner      *unstructured.Unstructured
+	extensions []extensionsv1alpha1.Extension
+	updated    *unstructured.Unstructured
+	deleted    bool
+}
+
+func (c *fakeOwnerClient) Get(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	*obj.(*unstructured.Unstructured) = *c.owner.DeepCopy()
+	return nil
+}
+
+func (c *fakeOwnerClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.updated = obj.(*unstructured.Unstructured).DeepCopy()
+	c.owner = c.updated
+	return nil
+}
+
+func (c *fakeOwnerClient) Delete(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+	c.deleted = true
+	return nil
+}
+
+func (c *fakeOwnerClient) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	list.(*extensionsv1alpha1.ExtensionList).Items = c.extensions
+	return nil
+}
+
+func newInactiveOwner() *unstructured.Unstructured {
+	owner := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"active": false},
+	}}
+	owner.SetGroupVersionKind(dnsOwnerGVK)
+	owner.SetName("owner")
+	owner.SetNamespace("shoot--foo--bar")
+	return owner
+}
+
+func reconcileRequest() reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "shoot--foo--bar", Name: "owner"}}
+}
+
+func TestReconcileDeprecateWarnActivatesButDoesNotRemove(t *testing.T) {
+	c := &fakeOwnerClient{owner: newInactiveOwner()}
+	r := &reconciler{client: c, mode: CleanupModeDeprecateWarn, log: logr.Discard()}
+
+	if _, err := r.Reconcile(context.Background(), reconcileRequest()); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	active, _, _ := unstructured.NestedBool(c.updated.Object, "spec", "active")
+	if !active {
+		t.Fatal("expected deprecate-warn mode to activate the DNSOwner")
+	}
+	if c.deleted {
+		t.Fatal("expected deprecate-warn mode to never remove the DNSOwner")
+	}
+}
+
+func TestReconcileMigrateAndRemoveDeletesWhenNotMigrating(t *testing.T) {
+	c := &fakeOwnerClient{owner: newInactiveOwner()}
+	r := &reconciler{client: c, mode: CleanupModeMigrateAndRemove, log: logr.Discard()}
+
+	if _, err := r.Reconcile(context.Background(), reconcileRequest()); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	if !c.deleted {
+		t.Fatal("expected migrate-and-remove mode to delete the DNSOwner once the shoot is not migrating")
+	}
+}
+
+func TestReconcileMigrateAndRemoveKeepsOwnerWhileMigrating(t *testing.T) {
+	c := &fakeOwnerClient{
+		owner: newInactiveOwner(),
+		extensions: []extensionsv1alpha1.Extension{{
+			Status: extensionsv1alpha1.ExtensionStatus{
+				DefaultStatus: gardencorev1beta1.DefaultStatus{
+					LastOperation: &gardencorev1beta1.LastOperation{Type: "Migrate"},
+				},
+			},
+		}},
+	}
+	r := &reconciler{client: c, mode: CleanupModeMigrateAndRemove, log: logr.Discard()}
+
+	if _, err := r.Reconcile(context.Background(), reconcileRequest()); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	if c.deleted {
+		t.Fatal("expected migrate-and-remove mode to keep the DNSOwner while the shoot is migrating")
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plan computes the difference between the DNSEntries a shoot's sources want and
+// the DNSEntries that already exist in the seed, so that large record churn can be
+// reviewed before it is applied. It is the dns-change-mode counterpart to a dry-run.
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	dnsv1alpha1 "github.com/gardener/external-dns-management/pkg/apis/dns/v1alpha1"
+)
+
+// ApprovedPlanHashAnnotation is set by an operator on the Extension resource to approve a
+// previously computed Plan for application in Mode require-approval. Its value must match
+// Plan.Hash exactly, otherwise the plan is left unapplied.
+const ApprovedPlanHashAnnotation = "dns.extensions.gardener.cloud/approved-plan-hash"
+
+// Mode controls whether and how computed plans are applied.
+type Mode string
+
+const (
+	// ModeApply pushes every computed change to the provider immediately (current/default behavior).
+	ModeApply Mode = "apply"
+	// ModePlan only computes the diff and records it; it never applies changes.
+	ModePlan Mode = "plan"
+	// ModeRequireApproval applies the plan only once ApprovedPlanHashAnnotation on the
+	// Extension matches the computed Plan.Hash.
+	ModeRequireApproval Mode = "require-approval"
+)
+
+// Change describes one DNSEntry that needs to be created, updated, or removed to reach the
+// desired state for a zone.
+type Change struct {
+	Name    string   `json:"name"`
+	DNSName string   `json:"dnsName"`
+	Targets []string `json:"targets,omitempty"`
+}
+
+// ZonePlan groups the changes required for a single DNS zone.
+type ZonePlan struct {
+	Zone      string   `json:"zone"`
+	Additions []Change `json:"additions,omitempty"`
+	Removals  []Change `json:"removals,omitempty"`
+	Updates   []Change `json:"updates,omitempty"`
+}
+
+// Plan is the computed, deterministically hashed set of changes across all zones managed
+// for a shoot.
+type Plan struct {
+	Zones []ZonePlan `json:"zones"`
+	Hash  string     `json:"hash"`
+}
+
+// IsEmpty reports whether the plan contains no changes at all.
+func (p *Plan) IsEmpty() bool {
+	for _, z := range p.Zones {
+		if len(z.Additions) > 0 || len(z.Removals) > 0 || len(z.Updates) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Compute compares the desired DNSEntries derived from shoot sources against the current
+// DNSEntry set in the seed, grouping the differences per zone and hashing the result
+// deterministically so it can be recorded on the extension status and later approved.
+func Compute(desired, current []dnsv1alpha1.DNSEntry) (*Plan, error) {
+	desiredByName := indexByName(desired)
+	currentByName := indexByName(current)
+
+	zones := map[string]*ZonePlan{}
+	zoneFor := func(zone string) *ZonePlan {
+		if z, ok := zones[zone]; ok {
+			return z
+		}
+		z := &ZonePlan{Zone: zone}
+		zones[zone] = z
+		return z
+	}
+
+	for name, entry := range desiredByName {
+		zone := zoneOf(entry)
+		if existing, ok := currentByName[name]; !ok {
+			zoneFor(zone).Additions = append(zoneFor(zone).Additions, changeOf(entry))
+		} else if !targetsEqual(existing.Spec.Targets, entry.Spec.Targets) {
+			zoneFor(zone).Updates = append(zoneFor(zone).Updates, changeOf(entry))
+		}
+	}
+	for name, entry := range currentByName {
+		if _, ok := desiredByName[name]; !ok {
+			zone := zoneOf(entry)
+			zoneFor(zone).Removals = append(zoneFor(zone).Removals, changeOf(entry))
+		}
+	}
+
+	p := &Plan{}
+	for zone := range zones {
+		p.Zones = append(p.Zones, *zones[zone])
+	}
+	sort.Slice(p.Zones, func(i, j int) bool { return p.Zones[i].Zone < p.Zones[j].Zone })
+	for _, z := range p.Zones {
+		sortChanges(z.Additions)
+		sortChanges(z.Removals)
+		sortChanges(z.Updates)
+	}
+
+	hash, err := hashPlan(p)
+	if err != nil {
+		return nil, fmt.Errorf("hashing plan: %w", err)
+	}
+	p.Hash = hash
+	return p, nil
+}
+
+// IsApproved reports whether the annotations on the Extension resource approve this plan
+// for application, i.e. whether they carry ApprovedPlanHashAnnotation with a matching hash.
+func (p *Plan) IsApproved(annotations map[string]string) bool {
+	return annotations[ApprovedPlanHashAnnotation] == p.Hash
+}
+
+func hashPlan(p *Plan) (string, error) {
+	unhashed := *p
+	unhashed.Hash = ""
+	data, err := json.Marshal(unhashed)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func indexByName(entries []dnsv1alpha1.DNSEntry) map[string]dnsv1alpha1.DNSEntry {
+	out := make(map[string]dnsv1alpha1.DNSEntry, len(entries))
+	for _, e := range entries {
+		out[e.Namespace+"/"+e.Name] = e
+	}
+	return out
+}
+
+func changeOf(e dnsv1alpha1.DNSEntry) Change {
+	return Change{Name: e.Namespace + "/" + e.Name, DNSName: e.Spec.DNSName, Targets: e.Spec.Targets}
+}
+
+// zoneOf returns the DNS zone that owns the entry, preferring the zone external-dns-management
+// has already matched and recorded on the entry's status. It only falls back to stripping the
+// leftmost label of the DNS name (wrong for any name with more than one subdomain level under
+// the actual zone) for entries that have not been reconciled by external-dns-management yet and
+// therefore have no status zone to report.
+func zoneOf(e dnsv1alpha1.DNSEntry) string {
+	if e.Status.Zone != "" {
+		return e.Status.Zone
+	}
+	name := e.Spec.DNSName
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func targetsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA, sortedB := append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortChanges(changes []Change) {
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+}
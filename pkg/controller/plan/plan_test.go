@@ -0,0 +1,120 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"testing"
+
+	dnsv1alpha1 "github.com/gardener/external-dns-management/pkg/apis/dns/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func entry(namespace, name, dnsName string, targets ...string) dnsv1alpha1.DNSEntry {
+	return dnsv1alpha1.DNSEntry{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       dnsv1alpha1.DNSEntrySpec{DNSName: dnsName, Targets: targets},
+	}
+}
+
+func TestComputeHashIsDeterministic(t *testing.T) {
+	desired := []dnsv1alpha1.DNSEntry{
+		entry("shoot--foo", "b", "b.shoot.example.com", "1.1.1.1"),
+		entry("shoot--foo", "a", "a.shoot.example.com", "2.2.2.2"),
+	}
+
+	p1, err := Compute(desired, nil)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	p2, err := Compute(desired, nil)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+
+	if p1.Hash != p2.Hash {
+		t.Fatalf("expected the same plan to hash identically, got %q and %q", p1.Hash, p2.Hash)
+	}
+}
+
+func TestComputeHashChangesWithContent(t *testing.T) {
+	base, err := Compute([]dnsv1alpha1.DNSEntry{entry("shoot--foo", "a", "a.shoot.example.com", "1.1.1.1")}, nil)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	changed, err := Compute([]dnsv1alpha1.DNSEntry{entry("shoot--foo", "a", "a.shoot.example.com", "2.2.2.2")}, nil)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+
+	if base.Hash == changed.Hash {
+		t.Fatalf("expected different desired targets to produce different hashes, both were %q", base.Hash)
+	}
+}
+
+func TestComputeClassifiesAdditionsUpdatesAndRemovals(t *testing.T) {
+	desired := []dnsv1alpha1.DNSEntry{
+		entry("shoot--foo", "new", "new.shoot.example.com", "1.1.1.1"),
+		entry("shoot--foo", "changed", "changed.shoot.example.com", "2.2.2.2"),
+	}
+	current := []dnsv1alpha1.DNSEntry{
+		entry("shoot--foo", "changed", "changed.shoot.example.com", "9.9.9.9"),
+		entry("shoot--foo", "stale", "stale.shoot.example.com", "3.3.3.3"),
+	}
+
+	p, err := Compute(desired, current)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	if p.IsEmpty() {
+		t.Fatal("expected a non-empty plan")
+	}
+
+	var additions, updates, removals int
+	for _, z := range p.Zones {
+		additions += len(z.Additions)
+		updates += len(z.Updates)
+		removals += len(z.Removals)
+	}
+	if additions != 1 || updates != 1 || removals != 1 {
+		t.Fatalf("expected 1 addition, 1 update, 1 removal, got %d/%d/%d", additions, updates, removals)
+	}
+}
+
+func TestComputeGroupsByStatusZoneWhenAvailable(t *testing.T) {
+	e := entry("shoot--foo", "a", "api.internal.shoot.example.com", "1.1.1.1")
+	e.Status.Zone = "example.com"
+
+	p, err := Compute([]dnsv1alpha1.DNSEntry{e}, nil)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	if len(p.Zones) != 1 || p.Zones[0].Zone != "example.com" {
+		t.Fatalf("expected entries to group by status.zone %q, got %+v", "example.com", p.Zones)
+	}
+}
+
+func TestIsApproved(t *testing.T) {
+	p := &Plan{Hash: "abc123"}
+
+	if p.IsApproved(nil) {
+		t.Fatal("expected no annotations to mean not approved")
+	}
+	if !p.IsApproved(map[string]string{ApprovedPlanHashAnnotation: "abc123"}) {
+		t.Fatal("expected a matching annotation to approve the plan")
+	}
+	if p.IsApproved(map[string]string{ApprovedPlanHashAnnotation: "different"}) {
+		t.Fatal("expected a mismatched hash to not approve the plan")
+	}
+}
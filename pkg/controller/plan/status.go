@@ -0,0 +1,73 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// lastPlanAnnotation stores the most recently computed plan hash on the Extension
+// resource, so operators and tooling can see at a glance whether a new plan has appeared
+// since the last one they reviewed.
+const lastPlanAnnotation = "dns.extensions.gardener.cloud/last-plan-hash"
+
+// ProviderStatus is the shape recorded in the Extension's status.providerStatus while
+// dns-change-mode is 'plan' or 'require-approval', so the full computed plan - not just its
+// hash - survives for operators and tooling to inspect without reading back the event.
+type ProviderStatus struct {
+	LastPlan *Plan `json:"lastPlan,omitempty"`
+}
+
+// Record stores the given plan on the Extension's status and annotations and emits an event
+// describing it, without applying any of the computed changes. If the plan's hash is already
+// recorded in the last-plan annotation, Record does nothing and emits no event.
+func Record(ctx context.Context, c client.Client, recorder record.EventRecorder, ext *extensionsv1alpha1.Extension, p *Plan) error {
+	if ext.Annotations[lastPlanAnnotation] == p.Hash {
+		return nil
+	}
+
+	patch := client.MergeFrom(ext.DeepCopy())
+	if ext.Annotations == nil {
+		ext.Annotations = map[string]string{}
+	}
+	ext.Annotations[lastPlanAnnotation] = p.Hash
+	if err := c.Patch(ctx, ext, patch); err != nil {
+		return fmt.Errorf("recording plan hash on extension %s/%s: %w", ext.Namespace, ext.Name, err)
+	}
+
+	statusPatch := client.MergeFrom(ext.DeepCopy())
+	raw, err := json.Marshal(&ProviderStatus{LastPlan: p})
+	if err != nil {
+		return fmt.Errorf("marshalling plan status: %w", err)
+	}
+	ext.Status.ProviderStatus = &runtime.RawExtension{Raw: raw}
+	if err := c.Status().Patch(ctx, ext, statusPatch); err != nil {
+		return fmt.Errorf("recording plan on extension %s/%s status: %w", ext.Namespace, ext.Name, err)
+	}
+
+	summary, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshalling plan: %w", err)
+	}
+	recorder.Eventf(ext, "Normal", "DNSChangePlanned", "computed dns change plan %s: %s", p.Hash, summary)
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package providers gathers DNSProvider and DNSEntry status across shoot control-plane
+// namespaces concurrently, so that a single slow or hanging provider cannot stall the
+// reconciliation of unrelated shoots.
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	inFlightFetches = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "shoot_dns_service_provider_fetches_in_flight",
+		Help: "Number of DNSProvider/DNSEntry status fetches currently in flight.",
+	})
+	fetchLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "shoot_dns_service_provider_fetch_duration_seconds",
+		Help:    "Latency of a single DNSProvider/DNSEntry status fetch, by namespace.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"namespace"})
+)
+
+func init() {
+	prometheus.MustRegister(inFlightFetches, fetchLatency)
+}
+
+// Result is the outcome of fetching DNSProvider and DNSEntry status for a single shoot
+// control-plane namespace.
+type Result struct {
+	Namespace string
+	Providers []unstructured.Unstructured
+	Entries   []unstructured.Unstructured
+	Err       error
+}
+
+// Fetcher concurrently lists DNSProvider and DNSEntry status across shoot control-plane
+// namespaces behind a bounded worker pool.
+type Fetcher struct {
+	parallelism int
+	timeout     time.Duration
+}
+
+// NewFetcher creates a new Fetcher that runs at most parallelism fetches at a time, each
+// bounded by the given per-namespace timeout.
+func NewFetcher(parallelism int, timeout time.Duration) *Fetcher {
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	return &Fetcher{parallelism: parallelism, timeout: timeout}
+}
+
+// FetchAll gathers DNSProvider and DNSEntry status for the given shoot control-plane
+// namespaces concurrently, bounded by the fetcher's parallelism. Errors are reported
+// per namespace in the returned Result, so a failure in one namespace does not prevent
+// the others from completing; ctx cancellation stops any fetches still in flight.
+func (f *Fetcher) FetchAll(ctx context.Context, c client.Client, namespaces []string) ([]Result, error) {
+	results := make([]Result, len(namespaces))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(f.parallelism)
+
+	for i, namespace := range namespaces {
+		i, namespace := i, namespace
+		group.Go(func() error {
+			results[i] = f.fetchOne(groupCtx, c, namespace)
+			return nil
+		})
+	}
+	// group.Go closures never return an error themselves; every failure is captured
+	// per-namespace in Result.Err instead, so Wait always returns nil and cancellation is
+	// checked on ctx below.
+	_ = group.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return results, fmt.Errorf("gathering dns provider status cancelled: %w", err)
+	}
+	return results, nil
+}
+
+func (f *Fetcher) fetchOne(ctx context.Context, c client.Client, namespace string) Result {
+	inFlightFetches.Inc()
+	defer inFlightFetches.Dec()
+
+	start := time.Now()
+	defer func() { fetchLatency.WithLabelValues(namespace).Observe(time.Since(start).Seconds()) }()
+
+	fetchCtx := ctx
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
+
+	providerList := &unstructured.UnstructuredList{}
+	providerList.SetAPIVersion("dns.gardener.cloud/v1alpha1")
+	providerList.SetKind("DNSProviderList")
+	if err := c.List(fetchCtx, providerList, client.InNamespace(namespace)); err != nil {
+		return Result{Namespace: namespace, Err: fmt.Errorf("listing dns providers in %s: %w", namespace, err)}
+	}
+
+	entryList := &unstructured.UnstructuredList{}
+	entryList.SetAPIVersion("dns.gardener.cloud/v1alpha1")
+	entryList.SetKind("DNSEntryList")
+	if err := c.List(fetchCtx, entryList, client.InNamespace(namespace)); err != nil {
+		return Result{Namespace: namespace, Err: fmt.Errorf("listing dns entries in %s: %w", namespace, err)}
+	}
+
+	return Result{Namespace: namespace, Providers: providerList.Items, Entries: entryList.Items}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// failingNamespaceClient fails List for any namespace in failNamespaces and otherwise
+// succeeds with an empty list. Only List is overridden; every other client.Client method is
+// unreachable from FetchAll and is left to the nil embedded interface.
+type failingNamespaceClient struct {
+	client.Client
+	failNamespaces map[string]bool
+}
+
+func (f *failingNamespaceClient) List(_ context.Context, _ client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	for _, opt := range opts {
+		opt.ApplyToList(listOpts)
+	}
+	if f.failNamespaces[listOpts.Namespace] {
+		return fmt.Errorf("boom listing in %s", listOpts.Namespace)
+	}
+	return nil
+}
+
+func TestFetchAllReportsPerNamespaceErrors(t *testing.T) {
+	c := &failingNamespaceClient{failNamespaces: map[string]bool{"broken": true}}
+	f := NewFetcher(2, time.Second)
+
+	results, err := f.FetchAll(context.Background(), c, []string{"ok-1", "broken", "ok-2"})
+	if err != nil {
+		t.Fatalf("FetchAll returned an overall error, expected per-namespace errors instead: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	byNamespace := map[string]Result{}
+	for _, r := range results {
+		byNamespace[r.Namespace] = r
+	}
+
+	if byNamespace["broken"].Err == nil {
+		t.Fatal("expected the broken namespace to report an error")
+	}
+	if byNamespace["ok-1"].Err != nil {
+		t.Fatalf("expected ok-1 to succeed despite broken's failure, got %v", byNamespace["ok-1"].Err)
+	}
+	if byNamespace["ok-2"].Err != nil {
+		t.Fatalf("expected ok-2 to succeed despite broken's failure, got %v", byNamespace["ok-2"].Err)
+	}
+}
+
+func TestNewFetcherClampsNonPositiveParallelism(t *testing.T) {
+	f := NewFetcher(0, time.Second)
+	if f.parallelism != 1 {
+		t.Fatalf("expected parallelism 0 to clamp to 1, got %d", f.parallelism)
+	}
+
+	f = NewFetcher(-5, time.Second)
+	if f.parallelism != 1 {
+		t.Fatalf("expected negative parallelism to clamp to 1, got %d", f.parallelism)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Name is the name of the replication controller.
+const Name = "shoot_dns_service_replication"
+
+// Type is the extension type this controller reconciles.
+const Type = "shoot-dns-service"
+
+// AddToManager adds the replication controller to the given manager.
+func AddToManager(mgr manager.Manager) error {
+	r := &Reconciler{
+		seedClient:      mgr.GetClient(),
+		recorder:        mgr.GetEventRecorderFor(Name),
+		fetcher:         DefaultAddOptions.Fetcher,
+		shootClientFunc: NewShootClient,
+	}
+
+	c, err := controller.New(Name, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: DefaultAddOptions.Controller.MaxConcurrentReconciles,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create %s controller: %w", Name, err)
+	}
+
+	return c.Watch(source.Kind(mgr.GetCache(), &extensionsv1alpha1.Extension{}), &handler.EnqueueRequestForObject{})
+}
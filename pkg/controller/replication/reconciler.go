@@ -0,0 +1,104 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"context"
+	"fmt"
+
+	dnsv1alpha1 "github.com/gardener/external-dns-management/pkg/apis/dns/v1alpha1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/gardener/gardener-extension-shoot-dns-service/pkg/controller/providers"
+)
+
+// Reconciler replicates DNSProviders from a shoot cluster into its seed control-plane
+// namespace. It first gathers the current seed-side DNSProviders through the bounded Fetcher
+// and, after copying every shoot DNSProvider's spec into the seed, deletes the seed-side
+// DNSProviders that no longer exist in the shoot.
+type Reconciler struct {
+	seedClient      client.Client
+	recorder        record.EventRecorder
+	fetcher         *providers.Fetcher
+	shootClientFunc shootClientFunc
+}
+
+// shootClientFunc builds a client for the shoot cluster whose control plane lives in the
+// given seed namespace. It is a field on Reconciler so tests can substitute a fake client.
+type shootClientFunc func(ctx context.Context, seedClient client.Client, namespace string) (client.Client, error)
+
+// Reconcile replicates the DNSProviders of the shoot owning req.Namespace into the seed.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ext := &extensionsv1alpha1.Extension{}
+	if err := r.seedClient.Get(ctx, req.NamespacedName, ext); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if ext.Spec.Type != Type {
+		return reconcile.Result{}, nil
+	}
+
+	results, err := r.fetcher.FetchAll(ctx, r.seedClient, []string{req.Namespace})
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("fetching seed dns provider status for %s: %w", req.Namespace, err)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			r.recorder.Eventf(ext, "Warning", "DNSStatusFetchFailed", "%v", result.Err)
+			return reconcile.Result{}, result.Err
+		}
+	}
+
+	shootClient, err := r.shootClientFunc(ctx, r.seedClient, req.Namespace)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("obtaining shoot client for %s: %w", req.Namespace, err)
+	}
+
+	providerList := &dnsv1alpha1.DNSProviderList{}
+	if err := shootClient.List(ctx, providerList); err != nil {
+		return reconcile.Result{}, fmt.Errorf("listing shoot dns providers: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(providerList.Items))
+	for _, shootProvider := range providerList.Items {
+		wanted[shootProvider.Name] = true
+		seedProvider := &dnsv1alpha1.DNSProvider{ObjectMeta: metav1.ObjectMeta{Name: shootProvider.Name, Namespace: req.Namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.seedClient, seedProvider, func() error {
+			seedProvider.Spec = shootProvider.Spec
+			return nil
+		}); err != nil {
+			return reconcile.Result{}, fmt.Errorf("replicating dns provider %s: %w", shootProvider.Name, err)
+		}
+	}
+
+	for _, result := range results {
+		for _, existing := range result.Providers {
+			if wanted[existing.GetName()] {
+				continue
+			}
+			seedProvider := &dnsv1alpha1.DNSProvider{ObjectMeta: metav1.ObjectMeta{Name: existing.GetName(), Namespace: req.Namespace}}
+			if err := r.seedClient.Delete(ctx, seedProvider); err != nil && !apierrors.IsNotFound(err) {
+				return reconcile.Result{}, fmt.Errorf("removing replicated dns provider %s: %w", existing.GetName(), err)
+			}
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/gardener/extensions/pkg/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewShootClient builds a client for the shoot cluster whose control plane lives in the
+// given seed namespace, using the same kubeconfig lookup the replication controller relies
+// on to read the DNSProviders it replicates from the shoot.
+func NewShootClient(ctx context.Context, seedClient client.Client, namespace string) (client.Client, error) {
+	_, shootClient, err := util.NewClientForShoot(ctx, seedClient, namespace, client.Options{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating shoot client for %s: %w", namespace, err)
+	}
+	return shootClient, nil
+}